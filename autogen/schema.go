@@ -0,0 +1,52 @@
+package autogen
+
+// ConfigurationSchema is the JSON Schema describing a valid
+// types.Configuration, used to validate the configuration that results
+// from a PATCH or DELETE on the REST provider before it is forwarded on
+// the configuration channel.
+//
+// This is hand-maintained, not generated — keep it in sync with
+// types.Configuration's fields and tags by hand when that struct changes.
+var ConfigurationSchema = []byte(`{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "title": "Configuration",
+  "type": "object",
+  "properties": {
+    "backends": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "servers": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "object",
+              "properties": {
+                "url": {"type": "string"},
+                "weight": {"type": "integer"}
+              },
+              "required": ["url"]
+            }
+          },
+          "loadBalancer": {"type": "object"},
+          "circuitBreaker": {"type": "object"},
+          "maxConn": {"type": "object"},
+          "healthCheck": {"type": "object"}
+        }
+      }
+    },
+    "frontends": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "backend": {"type": "string"},
+          "routes": {"type": "object"},
+          "priority": {"type": "integer"},
+          "passHostHeader": {"type": "boolean"}
+        },
+        "required": ["backend"]
+      }
+    }
+  }
+}`)
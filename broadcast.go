@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/middlewares/metrics"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+// configEvent is what gets pushed down the SSE stream. Kind is either
+// "config" (a configuration reload happened) or "health" (a backend
+// server flipped up/down).
+type configEvent struct {
+	Kind    string   `json:"kind"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// configBroadcaster fans a single stream of configEvents out to any
+// number of SSE subscribers. wake lets anything that just pushed a new
+// "web" provider configuration onto configurationChan (the PUT/PATCH/
+// DELETE handlers in web.go/web_patch.go) ask watchConfigurations to
+// re-check currentConfigurations right away, instead of waiting for the
+// next tick.
+type configBroadcaster struct {
+	mu   sync.Mutex
+	subs map[chan configEvent]struct{}
+
+	wake chan struct{}
+}
+
+func newConfigBroadcaster() *configBroadcaster {
+	return &configBroadcaster{
+		subs: make(map[chan configEvent]struct{}),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// nudge asks watchConfigurations to check currentConfigurations now
+// rather than on its next tick. It never blocks: if a nudge is already
+// pending, this one is redundant and dropped.
+func (b *configBroadcaster) nudge() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// subscribe registers a new listener and returns its channel along with
+// a function that must be called to unregister it.
+func (b *configBroadcaster) subscribe() (chan configEvent, func()) {
+	ch := make(chan configEvent, 8)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		close(ch)
+	}
+	return ch, unsubscribe
+}
+
+func (b *configBroadcaster) publish(event configEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber, drop the event rather than block the
+			// whole broadcaster.
+		}
+	}
+}
+
+// watchConfigurations reacts to nudge and publishes a diff as soon as it
+// sees one in currentConfigurations. The REST API's own PUT/PATCH/DELETE
+// handlers call nudge right after they hand a new "web" configuration to
+// configurationChan, so that path is event-driven. Other providers feed
+// configurationChan too, and nothing in this package is notified when
+// the code that merges all providers' configs into currentConfigurations
+// (outside this package, in server.go) finishes a generation for one of
+// them — so a slow fallback tick stays in place to pick those up without
+// depending on a hook this package doesn't have. It runs until stop
+// fires; callers run it in a safe.Pool goroutine.
+func (b *configBroadcaster) watchConfigurations(currentConfigurations *safe.Safe, registry metrics.Registry, stop chan bool) {
+	var previous configs
+	fallback := time.NewTicker(5 * time.Second)
+	defer fallback.Stop()
+
+	check := func() {
+		current, ok := currentConfigurations.Get().(configs)
+		if !ok {
+			return
+		}
+		if event, changed := diffConfigurations(previous, current); changed {
+			b.publish(event)
+		}
+		if healthEvent, flipped := diffBackendServerHealth(registry, previous, current); flipped {
+			b.publish(healthEvent)
+		}
+		previous = current
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-b.wake:
+			check()
+		case <-fallback.C:
+			check()
+		}
+	}
+}
+
+// diffBackendServerHealth compares the set of backend servers between
+// two generations of configs, tells registry about every server that
+// came up or went down, and returns the "health" event to publish on
+// the SSE stream for the same transition.
+func diffBackendServerHealth(registry metrics.Registry, previous, current configs) (configEvent, bool) {
+	event := configEvent{Kind: "health"}
+
+	currentServers := serverSet(current)
+	previousServers := serverSet(previous)
+
+	for key := range currentServers {
+		if _, wasUp := previousServers[key]; !wasUp {
+			registry.BackendServerUp(key[0], key[1], true)
+			event.Added = append(event.Added, fmt.Sprintf("%s/%s", key[0], key[1]))
+		}
+	}
+	for key := range previousServers {
+		if _, stillUp := currentServers[key]; !stillUp {
+			registry.BackendServerUp(key[0], key[1], false)
+			event.Removed = append(event.Removed, fmt.Sprintf("%s/%s", key[0], key[1]))
+		}
+	}
+
+	flipped := len(event.Added) > 0 || len(event.Removed) > 0
+	return event, flipped
+}
+
+func serverSet(confs configs) map[[2]string]struct{} {
+	servers := make(map[[2]string]struct{})
+	for _, conf := range confs {
+		for backendName, backend := range conf.Backends {
+			for serverName := range backend.Servers {
+				servers[[2]string{backendName, serverName}] = struct{}{}
+			}
+		}
+	}
+	return servers
+}
+
+// diffConfigurations compares two generations of configs and reports
+// which frontends/backends were added, removed or changed.
+func diffConfigurations(previous, current configs) (configEvent, bool) {
+	event := configEvent{Kind: "config"}
+
+	for providerName, conf := range current {
+		oldConf, existed := previous[providerName]
+		if !existed {
+			event.Added = append(event.Added, providerName)
+			continue
+		}
+		if !sameFrontendsAndBackends(oldConf, conf) {
+			event.Changed = append(event.Changed, providerName)
+		}
+	}
+	for providerName := range previous {
+		if _, stillThere := current[providerName]; !stillThere {
+			event.Removed = append(event.Removed, providerName)
+		}
+	}
+
+	changed := len(event.Added) > 0 || len(event.Removed) > 0 || len(event.Changed) > 0
+	return event, changed
+}
+
+// sameFrontendsAndBackends reports whether a and b define the exact
+// same frontends and backends, content included — not just the same
+// set of names. This is what makes in-place edits to an existing
+// frontend/backend (a server's URL changing, a route being added, ...)
+// show up as a "changed" provider instead of going unnoticed.
+func sameFrontendsAndBackends(a, b *types.Configuration) bool {
+	if len(a.Frontends) != len(b.Frontends) || len(a.Backends) != len(b.Backends) {
+		return false
+	}
+	for name, frontend := range a.Frontends {
+		other, ok := b.Frontends[name]
+		if !ok || !reflect.DeepEqual(frontend, other) {
+			return false
+		}
+	}
+	for name, backend := range a.Backends {
+		other, ok := b.Backends[name]
+		if !ok || !reflect.DeepEqual(backend, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/middlewares/metrics"
+	"github.com/containous/traefik/types"
+)
+
+func TestSameFrontendsAndBackendsDetectsContentChanges(t *testing.T) {
+	a := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+		Frontends: map[string]*types.Frontend{},
+	}
+	b := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.2:80"},
+			}},
+		},
+		Frontends: map[string]*types.Frontend{},
+	}
+
+	if sameFrontendsAndBackends(a, b) {
+		t.Fatal("expected a changed server URL on an existing backend to be detected, but it was not")
+	}
+}
+
+func TestSameFrontendsAndBackendsIgnoresIdenticalContent(t *testing.T) {
+	build := func() *types.Configuration {
+		return &types.Configuration{
+			Backends: map[string]*types.Backend{
+				"backend1": {Servers: map[string]*types.Server{
+					"server1": {URL: "http://10.0.0.1:80"},
+				}},
+			},
+			Frontends: map[string]*types.Frontend{
+				"frontend1": {Backend: "backend1"},
+			},
+		}
+	}
+
+	if !sameFrontendsAndBackends(build(), build()) {
+		t.Fatal("expected two configurations with identical content to compare equal")
+	}
+}
+
+func TestDiffConfigurationsReportsAddedRemovedAndChanged(t *testing.T) {
+	previous := configs{
+		"web": {
+			Backends:  map[string]*types.Backend{"backend1": {}},
+			Frontends: map[string]*types.Frontend{},
+		},
+		"removed-provider": {
+			Backends:  map[string]*types.Backend{},
+			Frontends: map[string]*types.Frontend{},
+		},
+	}
+	current := configs{
+		"web": {
+			Backends: map[string]*types.Backend{
+				"backend1": {Servers: map[string]*types.Server{"server1": {URL: "http://10.0.0.1:80"}}},
+			},
+			Frontends: map[string]*types.Frontend{},
+		},
+		"added-provider": {
+			Backends:  map[string]*types.Backend{},
+			Frontends: map[string]*types.Frontend{},
+		},
+	}
+
+	event, changed := diffConfigurations(previous, current)
+	if !changed {
+		t.Fatal("expected a diff to be reported")
+	}
+	if len(event.Added) != 1 || event.Added[0] != "added-provider" {
+		t.Errorf("expected added-provider to be reported as added, got %v", event.Added)
+	}
+	if len(event.Removed) != 1 || event.Removed[0] != "removed-provider" {
+		t.Errorf("expected removed-provider to be reported as removed, got %v", event.Removed)
+	}
+	if len(event.Changed) != 1 || event.Changed[0] != "web" {
+		t.Errorf("expected web to be reported as changed, got %v", event.Changed)
+	}
+}
+
+func TestDiffConfigurationsNoChange(t *testing.T) {
+	current := configs{
+		"web": {
+			Backends:  map[string]*types.Backend{"backend1": {}},
+			Frontends: map[string]*types.Frontend{},
+		},
+	}
+	if _, changed := diffConfigurations(current, current); changed {
+		t.Fatal("expected no diff when nothing changed")
+	}
+}
+
+func TestNudgeIsNonBlockingAndCoalesces(t *testing.T) {
+	b := newConfigBroadcaster()
+
+	b.nudge()
+	b.nudge() // must not block even though the first nudge is still pending
+
+	select {
+	case <-b.wake:
+	default:
+		t.Fatal("expected a pending nudge on the wake channel")
+	}
+
+	select {
+	case <-b.wake:
+		t.Fatal("expected the second nudge to coalesce with the first, not queue separately")
+	default:
+	}
+}
+
+type recordingRegistry struct {
+	metrics.Registry
+	up map[[2]string]bool
+}
+
+func (r *recordingRegistry) BackendServerUp(backend, server string, up bool) {
+	if r.up == nil {
+		r.up = make(map[[2]string]bool)
+	}
+	r.up[[2]string{backend, server}] = up
+}
+
+func TestDiffBackendServerHealthReportsFlips(t *testing.T) {
+	previous := configs{
+		"web": {
+			Backends: map[string]*types.Backend{
+				"backend1": {Servers: map[string]*types.Server{
+					"server1": {URL: "http://10.0.0.1:80"},
+				}},
+			},
+		},
+	}
+	current := configs{
+		"web": {
+			Backends: map[string]*types.Backend{
+				"backend1": {Servers: map[string]*types.Server{
+					"server2": {URL: "http://10.0.0.2:80"},
+				}},
+			},
+		},
+	}
+
+	registry := &recordingRegistry{}
+	event, flipped := diffBackendServerHealth(registry, previous, current)
+	if !flipped {
+		t.Fatal("expected a health flip to be reported")
+	}
+	if up, ok := registry.up[[2]string{"backend1", "server2"}]; !ok || !up {
+		t.Errorf("expected server2 to be reported up, got %v", registry.up)
+	}
+	if up, ok := registry.up[[2]string{"backend1", "server1"}]; !ok || up {
+		t.Errorf("expected server1 to be reported down, got %v", registry.up)
+	}
+	if len(event.Added) != 1 || len(event.Removed) != 1 {
+		t.Errorf("expected one server added and one removed in the health event, got %+v", event)
+	}
+}
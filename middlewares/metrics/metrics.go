@@ -0,0 +1,40 @@
+// Package metrics exposes traefik's internal counters through a small,
+// pluggable Registry so that the web provider does not need to know
+// whether it is talking to Prometheus, the legacy thoas/stats backend,
+// or (in the future) something else entirely.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+// Registry has to be implemented by any system that wants to receive
+// traefik metrics.
+type Registry interface {
+	// Handler returns the http.Handler to be mounted on the web
+	// provider's /metrics route.
+	Handler() http.Handler
+
+	ReqsCounter(frontend, backend, method, statusCode string)
+	ReqDurationHistogram(frontend, backend string, duration time.Duration)
+	RespSizeHistogram(frontend, backend string, size float64)
+	InFlightReqs(frontend, backend string, delta int)
+	BackendServerUp(backend, server string, up bool)
+	ConfigReloadsCounter()
+	ConfigReloadsErrorsCounter()
+	Goroutines(n int)
+}
+
+// RegistryFor returns the Registry selected by the given configuration.
+// When no Prometheus backend is configured it falls back to the
+// thoas/stats based registry. /health does not depend on this choice:
+// the web provider serves it from its own stats recorder.
+func RegistryFor(metricsConfig *types.Metrics) Registry {
+	if metricsConfig != nil && metricsConfig.Prometheus != nil {
+		return newPrometheusRegistry()
+	}
+	return newStatsRegistry()
+}
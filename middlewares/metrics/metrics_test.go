@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestRegistryForFallsBackToStatsWithoutAMetricsConfig(t *testing.T) {
+	if _, ok := RegistryFor(nil).(*statsRegistry); !ok {
+		t.Fatal("expected a nil metrics configuration to fall back to the stats registry")
+	}
+}
+
+func TestRegistryForSelectsPrometheusWhenConfigured(t *testing.T) {
+	config := &types.Metrics{Prometheus: &types.Prometheus{}}
+	if _, ok := RegistryFor(config).(*prometheusRegistry); !ok {
+		t.Fatal("expected a Prometheus metrics configuration to select the Prometheus registry")
+	}
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codegangsta/negroni"
+)
+
+// NewEntryPoint returns a negroni-style handler that records request
+// count, duration, response size and in-flight requests against
+// registry, labeled with the given frontend/backend names.
+func NewEntryPoint(registry Registry, frontend, backend string) func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		start := time.Now()
+
+		registry.InFlightReqs(frontend, backend, 1)
+		defer registry.InFlightReqs(frontend, backend, -1)
+
+		next(w, r)
+
+		statusCode := http.StatusOK
+		size := 0
+		if rw, ok := w.(negroni.ResponseWriter); ok {
+			statusCode = rw.Status()
+			size = rw.Size()
+		}
+
+		registry.ReqsCounter(frontend, backend, r.Method, strconv.Itoa(statusCode))
+		registry.ReqDurationHistogram(frontend, backend, time.Since(start))
+		registry.RespSizeHistogram(frontend, backend, float64(size))
+	}
+}
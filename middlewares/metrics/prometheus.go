@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	prometheusNamespace = "traefik"
+)
+
+// prometheusRegistry is a Registry backed by the Prometheus client
+// library. Each instance registers its collectors against its own
+// *prometheus.Registry rather than the global default registerer, so
+// that building more than one (provider restart, tests) never panics
+// with a duplicate-collector registration.
+type prometheusRegistry struct {
+	registry             *prometheus.Registry
+	reqsCounter          *prometheus.CounterVec
+	reqDurationHistogram *prometheus.HistogramVec
+	respSizeHistogram    *prometheus.HistogramVec
+	inFlightReqsGauge    *prometheus.GaugeVec
+	backendServerUpGauge *prometheus.GaugeVec
+	configReloadsCounter prometheus.Counter
+	configErrorsCounter  prometheus.Counter
+	goroutinesGauge      prometheus.Gauge
+}
+
+func newPrometheusRegistry() *prometheusRegistry {
+	reqsCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "requests_total",
+		Help:      "How many HTTP requests processed, partitioned by frontend, backend, method and status code.",
+	}, []string{"frontend", "backend", "method", "code"})
+
+	reqDurationHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "request_duration_seconds",
+		Help:      "How long it took to process the request, partitioned by frontend and backend.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"frontend", "backend"})
+
+	respSizeHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "response_size_bytes",
+		Help:      "How big the response was, partitioned by frontend and backend.",
+		Buckets:   prometheus.ExponentialBuckets(100, 10, 6),
+	}, []string{"frontend", "backend"})
+
+	inFlightReqsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "requests_in_flight",
+		Help:      "How many requests are currently being served, partitioned by frontend and backend.",
+	}, []string{"frontend", "backend"})
+
+	backendServerUpGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "backend_server_up",
+		Help:      "Whether a backend server is seen as healthy (1) or not (0).",
+	}, []string{"backend", "server"})
+
+	configReloadsCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "config_reloads_total",
+		Help:      "How many configuration reloads were applied.",
+	})
+
+	configErrorsCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "config_reload_errors_total",
+		Help:      "How many configuration reloads failed.",
+	})
+
+	goroutinesGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "goroutines",
+		Help:      "How many goroutines are currently running.",
+	})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		reqsCounter,
+		reqDurationHistogram,
+		respSizeHistogram,
+		inFlightReqsGauge,
+		backendServerUpGauge,
+		configReloadsCounter,
+		configErrorsCounter,
+		goroutinesGauge,
+	)
+
+	return &prometheusRegistry{
+		registry:             registry,
+		reqsCounter:          reqsCounter,
+		reqDurationHistogram: reqDurationHistogram,
+		respSizeHistogram:    respSizeHistogram,
+		inFlightReqsGauge:    inFlightReqsGauge,
+		backendServerUpGauge: backendServerUpGauge,
+		configReloadsCounter: configReloadsCounter,
+		configErrorsCounter:  configErrorsCounter,
+		goroutinesGauge:      goroutinesGauge,
+	}
+}
+
+func (r *prometheusRegistry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+func (r *prometheusRegistry) ReqsCounter(frontend, backend, method, statusCode string) {
+	r.reqsCounter.WithLabelValues(frontend, backend, method, statusCode).Inc()
+}
+
+func (r *prometheusRegistry) ReqDurationHistogram(frontend, backend string, duration time.Duration) {
+	r.reqDurationHistogram.WithLabelValues(frontend, backend).Observe(duration.Seconds())
+}
+
+func (r *prometheusRegistry) RespSizeHistogram(frontend, backend string, size float64) {
+	r.respSizeHistogram.WithLabelValues(frontend, backend).Observe(size)
+}
+
+func (r *prometheusRegistry) InFlightReqs(frontend, backend string, delta int) {
+	r.inFlightReqsGauge.WithLabelValues(frontend, backend).Add(float64(delta))
+}
+
+func (r *prometheusRegistry) BackendServerUp(backend, server string, up bool) {
+	value := 0.0
+	if up {
+		value = 1.0
+	}
+	r.backendServerUpGauge.WithLabelValues(backend, server).Set(value)
+}
+
+func (r *prometheusRegistry) ConfigReloadsCounter() {
+	r.configReloadsCounter.Inc()
+}
+
+func (r *prometheusRegistry) ConfigReloadsErrorsCounter() {
+	r.configErrorsCounter.Inc()
+}
+
+func (r *prometheusRegistry) Goroutines(n int) {
+	r.goroutinesGauge.Set(float64(n))
+}
@@ -0,0 +1,17 @@
+package metrics
+
+import "testing"
+
+func TestNewPrometheusRegistryCanBeConstructedMoreThanOnce(t *testing.T) {
+	first := newPrometheusRegistry()
+	second := newPrometheusRegistry()
+
+	if first.registry == second.registry {
+		t.Fatal("expected each prometheusRegistry to own its own *prometheus.Registry")
+	}
+
+	// These must not panic even though both registries were built in the
+	// same process against the same collector names.
+	first.ReqsCounter("frontend1", "backend1", "GET", "200")
+	second.BackendServerUp("backend1", "server1", true)
+}
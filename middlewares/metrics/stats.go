@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thoas/stats"
+)
+
+// statsRegistry is the Registry implementation backed by thoas/stats.
+// It has no notion of labels: the per-frontend/backend calls, the
+// backend server health calls and Goroutines are no-ops. Handler serves
+// the data of this registry's own stats.Stats, which nothing in this
+// package records requests into. Config reloads and reload errors are
+// counted but not included in Handler's output.
+type statsRegistry struct {
+	stats *stats.Stats
+
+	mu                 sync.Mutex
+	configReloads      int64
+	configReloadErrors int64
+}
+
+func newStatsRegistry() *statsRegistry {
+	return &statsRegistry{
+		stats: stats.New(),
+	}
+}
+
+func (r *statsRegistry) Handler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(response).Encode(r.stats.Data())
+	})
+}
+
+func (r *statsRegistry) ReqsCounter(frontend, backend, method, statusCode string) {
+	// No-op: this registry's stats.Stats is not fed from here.
+}
+
+func (r *statsRegistry) ReqDurationHistogram(frontend, backend string, duration time.Duration) {}
+
+func (r *statsRegistry) RespSizeHistogram(frontend, backend string, size float64) {}
+
+func (r *statsRegistry) InFlightReqs(frontend, backend string, delta int) {}
+
+func (r *statsRegistry) BackendServerUp(backend, server string, up bool) {}
+
+func (r *statsRegistry) ConfigReloadsCounter() {
+	r.mu.Lock()
+	r.configReloads++
+	r.mu.Unlock()
+}
+
+func (r *statsRegistry) ConfigReloadsErrorsCounter() {
+	r.mu.Lock()
+	r.configReloadErrors++
+	r.mu.Unlock()
+}
+
+func (r *statsRegistry) Goroutines(n int) {}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+
+	"github.com/containous/traefik/types"
+)
+
+func newJaegerTracer(config *types.Jaeger, sampleRate float64) (*Tracer, error) {
+	cfg := jaegercfg.Configuration{
+		Sampler: &jaegercfg.SamplerConfig{
+			Type:              "probabilistic",
+			Param:             sampleRate,
+			SamplingServerURL: config.SamplingServerURL,
+		},
+		Reporter: &jaegercfg.ReporterConfig{
+			LocalAgentHostPort: config.LocalAgentHostPort,
+		},
+	}
+
+	opentracingTracer, closer, err := cfg.New("traefik")
+	if err != nil {
+		logStartError("jaeger", err)
+		return nil, err
+	}
+
+	return &Tracer{
+		Tracer:     opentracingTracer,
+		backend:    "jaeger",
+		sampleRate: sampleRate,
+		closer:     closer,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import "testing"
+
+func TestNewTracerWithNilConfigIsANoop(t *testing.T) {
+	tracer, err := NewTracer(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if tracer.backend != "none" {
+		t.Errorf("expected a nil configuration to yield the \"none\" backend, got %q", tracer.backend)
+	}
+	if err := tracer.Close(); err != nil {
+		t.Errorf("expected Close on a no-op tracer to be a no-op, got %+v", err)
+	}
+}
+
+func TestRecordTraceIDKeepsOnlyTheMostRecent(t *testing.T) {
+	tracer := &Tracer{backend: "jaeger"}
+
+	for i := 0; i < recentTraceIDsSize+5; i++ {
+		tracer.recordTraceID(string(rune('a' + i%26)))
+	}
+
+	report := tracer.Report()
+	if len(report.RecentTraceIDs) != recentTraceIDsSize {
+		t.Fatalf("expected recordTraceID to cap recentTraceIDs at %d, got %d", recentTraceIDsSize, len(report.RecentTraceIDs))
+	}
+}
+
+func TestReportReflectsBackendAndSampleRate(t *testing.T) {
+	tracer := &Tracer{backend: "zipkin", sampleRate: 0.5}
+
+	report := tracer.Report()
+	if report.Backend != "zipkin" {
+		t.Errorf("expected backend %q, got %q", "zipkin", report.Backend)
+	}
+	if report.SampleRate != 0.5 {
+		t.Errorf("expected sampleRate %v, got %v", 0.5, report.SampleRate)
+	}
+}
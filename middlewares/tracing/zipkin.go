@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	zipkin "github.com/openzipkin/zipkin-go-opentracing"
+
+	"github.com/containous/traefik/types"
+)
+
+func newZipkinTracer(config *types.Zipkin, sampleRate float64) (*Tracer, error) {
+	collector, err := zipkin.NewHTTPCollector(config.HTTPEndpoint)
+	if err != nil {
+		logStartError("zipkin", err)
+		return nil, err
+	}
+
+	recorder := zipkin.NewRecorder(collector, false, "0.0.0.0:0", "traefik")
+	opentracingTracer, err := zipkin.NewTracer(
+		recorder,
+		zipkin.WithSampler(zipkin.NewBoundarySampler(sampleRate, 0)),
+	)
+	if err != nil {
+		logStartError("zipkin", err)
+		return nil, err
+	}
+
+	return &Tracer{
+		Tracer:     opentracingTracer,
+		backend:    "zipkin",
+		sampleRate: sampleRate,
+		closer:     collector,
+	}, nil
+}
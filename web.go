@@ -7,11 +7,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime"
+	"time"
 
 	"github.com/codegangsta/negroni"
 	"github.com/containous/traefik/autogen"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/middlewares/metrics"
+	"github.com/containous/traefik/middlewares/tracing"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
 	"github.com/containous/traefik/version"
@@ -21,17 +24,20 @@ import (
 	"github.com/unrolled/render"
 )
 
-var metrics = stats.New()
+var statsRecorder = stats.New()
 
 // WebProvider is a provider.Provider implementation that provides the UI.
 // FIXME to be handled another way.
 type WebProvider struct {
-	Address  string `description:"Web administration port"`
-	CertFile string `description:"SSL certificate"`
-	KeyFile  string `description:"SSL certificate"`
-	ReadOnly bool   `description:"Enable read only API"`
-	server   *Server
-	Auth     *types.Auth
+	Address     string `description:"Web administration port"`
+	CertFile    string `description:"SSL certificate"`
+	KeyFile     string `description:"SSL certificate"`
+	ReadOnly    bool   `description:"Enable read only API"`
+	server      *Server
+	Auth        *types.Auth
+	metrics     metrics.Registry
+	tracer      *tracing.Tracer
+	broadcaster *configBroadcaster
 }
 
 var (
@@ -52,36 +58,64 @@ func goroutines() interface{} {
 // using the given configuration channel.
 func (provider *WebProvider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, _ []types.Constraint) error {
 
+	provider.metrics = metrics.RegistryFor(provider.server.globalConfiguration.Metrics)
+
+	tracer, err := tracing.NewTracer(provider.server.globalConfiguration.Tracing)
+	if err != nil {
+		return err
+	}
+	provider.tracer = tracer
+	pool.Go(func(stop chan bool) {
+		<-stop
+		if err := provider.tracer.Close(); err != nil {
+			log.Errorf("Error closing tracer: %+v", err)
+		}
+	})
+
+	provider.broadcaster = newConfigBroadcaster()
+	pool.Go(func(stop chan bool) {
+		provider.broadcaster.watchConfigurations(provider.server.currentConfigurations, provider.metrics, stop)
+	})
+
 	systemRouter := chi.NewRouter()
 
 	// health route
 	systemRouter.Get("/health", provider.getHealthHandler)
 
+	// metrics route
+	systemRouter.Get("/metrics", provider.getMetricsHandler)
+
 	// ping route
 	systemRouter.Get("/ping", provider.getPingHandler)
 	// API routes
 	systemRouter.Get("/api", provider.getConfigHandler)
 	systemRouter.Get("/api/version", provider.getVersionHandler)
+	systemRouter.Get("/api/stream", provider.getConfigStreamHandler)
+	systemRouter.Get("/api/tracing", provider.getTracingHandler)
 
 	systemRouter.Get("/api/providers", provider.getConfigHandler)
 
 	systemRouter.Route("/api/providers", func(r chi.Router) {
 		r.Get("/:provider", provider.getProviderHandler)
 		r.Put("/:provider", provider.makePutProviderHandler(configurationChan))
+		r.Patch("/:provider", provider.makePatchProviderHandler(configurationChan))
 
 		r.Route("/:provider", func(r chi.Router) {
 			r.Get("/backends", provider.getBackendsHandler)
 
 			r.Route("/backends", func(r chi.Router) {
 				r.Get("/:backend", provider.getBackendHandler)
+				r.Delete("/:backend", provider.makeDeleteBackendHandler(configurationChan))
 				r.Get("/:backend/servers", provider.getServersHandler)
 				r.Get("/:backend/servers/:server", provider.getServerHandler)
+				r.Delete("/:backend/servers/:server", provider.makeDeleteServerHandler(configurationChan))
 			})
 
 			r.Get("/frontends", provider.getFrontendsHandler)
 
 			r.Route("/frontends", func(r chi.Router) {
 				r.Get("/:frontend", provider.getFrontendHandler)
+				r.Delete("/:frontend", provider.makeDeleteFrontendHandler(configurationChan))
 				r.Get("/:frontend/routes", provider.getRoutesHandler)
 				r.Get("/:frontend/routes/:route", provider.getRouteHandler)
 			})
@@ -109,6 +143,8 @@ func (provider *WebProvider) Provide(configurationChan chan<- types.ConfigMessag
 			}
 			negroni.Use(authMiddleware)
 		}
+		negroni.UseFunc(metrics.NewEntryPoint(provider.metrics, "web", "web"))
+		negroni.UseFunc(provider.tracer.NewEntryPoint("web", "web", provider.Address))
 		negroni.UseHandler(systemRouter)
 
 		if len(provider.CertFile) > 0 && len(provider.KeyFile) > 0 {
@@ -125,7 +161,12 @@ func (provider *WebProvider) Provide(configurationChan chan<- types.ConfigMessag
 }
 
 func (provider *WebProvider) getHealthHandler(response http.ResponseWriter, request *http.Request) {
-	templatesRenderer.JSON(response, http.StatusOK, metrics.Data())
+	templatesRenderer.JSON(response, http.StatusOK, statsRecorder.Data())
+}
+
+func (provider *WebProvider) getMetricsHandler(response http.ResponseWriter, request *http.Request) {
+	provider.metrics.Goroutines(runtime.NumGoroutine())
+	provider.metrics.Handler().ServeHTTP(response, request)
 }
 
 func (provider *WebProvider) getPingHandler(response http.ResponseWriter, request *http.Request) {
@@ -137,6 +178,53 @@ func (provider *WebProvider) getConfigHandler(response http.ResponseWriter, requ
 	templatesRenderer.JSON(response, http.StatusOK, currentConfigurations)
 }
 
+// getConfigStreamHandler upgrades the connection to a Server-Sent Events
+// stream and pushes a "config" frame every time the current
+// configurations change, plus a comment-only heartbeat every 15s to
+// keep intermediate proxies from closing the connection.
+func (provider *WebProvider) getConfigStreamHandler(response http.ResponseWriter, request *http.Request) {
+	flusher, ok := response.(http.Flusher)
+	if !ok {
+		http.Error(response, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	response.Header().Set("Content-Type", "text/event-stream")
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("Connection", "keep-alive")
+	response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events, unsubscribe := provider.broadcaster.subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	closeNotify := request.Context().Done()
+	for {
+		select {
+		case <-closeNotify:
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Errorf("Error marshalling config event %+v", err)
+				continue
+			}
+			fmt.Fprintf(response, "event: %s\ndata: %s\n\n", event.Kind, data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(response, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (provider *WebProvider) getTracingHandler(response http.ResponseWriter, request *http.Request) {
+	templatesRenderer.JSON(response, http.StatusOK, provider.tracer.Report())
+}
+
 func (provider *WebProvider) getVersionHandler(response http.ResponseWriter, request *http.Request) {
 	v := struct {
 		Version  string
@@ -175,9 +263,12 @@ func (provider *WebProvider) makePutProviderHandler(configurationChan chan<- typ
 		body, _ := ioutil.ReadAll(request.Body)
 		err := json.Unmarshal(body, configuration)
 		if err == nil {
+			provider.metrics.ConfigReloadsCounter()
 			configurationChan <- types.ConfigMessage{ProviderName: "web", Configuration: configuration}
+			provider.broadcaster.nudge()
 			provider.getConfigHandler(response, request)
 		} else {
+			provider.metrics.ConfigReloadsErrorsCounter()
 			log.Errorf("Error parsing configuration %+v", err)
 			http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
 		}
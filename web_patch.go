@@ -0,0 +1,287 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/containous/traefik/autogen"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/pressly/chi"
+)
+
+// validationErrors is the body returned on a 422 when a patched/deleted
+// configuration does not satisfy the embedded JSON Schema.
+type validationErrors struct {
+	Errors []string `json:"errors"`
+}
+
+// makePatchProviderHandler accepts either a RFC 7396 JSON Merge Patch
+// (Content-Type: application/merge-patch+json) or a RFC 6902 JSON Patch
+// (Content-Type: application/json-patch+json), applies it on top of the
+// current "web" provider configuration, validates the result, and only
+// then forwards it on configurationChan.
+func (provider *WebProvider) makePatchProviderHandler(configurationChan chan<- types.ConfigMessage) func(response http.ResponseWriter, request *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		if provider.ReadOnly {
+			response.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(response, "REST API is in read-only mode")
+			return
+		}
+		if chi.URLParam(request, "provider") != "web" {
+			response.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(response, "Only 'web' provider can be updated through the REST API")
+			return
+		}
+
+		original := provider.getWebConfiguration()
+
+		originalJSON, err := json.Marshal(original)
+		if err != nil {
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusInternalServerError)
+			return
+		}
+
+		patch, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+			return
+		}
+
+		var patchedJSON []byte
+		switch request.Header.Get("Content-Type") {
+		case "application/json-patch+json":
+			decodedPatch, err := jsonpatch.DecodePatch(patch)
+			if err != nil {
+				http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+				return
+			}
+			patchedJSON, err = decodedPatch.Apply(originalJSON)
+			if err != nil {
+				http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+				return
+			}
+		default:
+			patchedJSON, err = jsonpatch.MergePatch(originalJSON, patch)
+			if err != nil {
+				http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		configuration := new(types.Configuration)
+		if err := json.Unmarshal(patchedJSON, configuration); err != nil {
+			log.Errorf("Error parsing patched configuration %+v", err)
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+			return
+		}
+
+		provider.applyValidatedConfiguration(response, request, configurationChan, configuration)
+	}
+}
+
+// makeDeleteBackendHandler removes a single backend from the "web"
+// provider configuration.
+func (provider *WebProvider) makeDeleteBackendHandler(configurationChan chan<- types.ConfigMessage) func(response http.ResponseWriter, request *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		configuration, err := provider.cloneWebConfiguration()
+		if err != nil {
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusInternalServerError)
+			return
+		}
+		backendID := chi.URLParam(request, "backend")
+		if _, ok := configuration.Backends[backendID]; !ok {
+			http.NotFound(response, request)
+			return
+		}
+		delete(configuration.Backends, backendID)
+		provider.applyValidatedConfiguration(response, request, configurationChan, configuration)
+	}
+}
+
+// makeDeleteServerHandler removes a single server from a backend of the
+// "web" provider configuration.
+func (provider *WebProvider) makeDeleteServerHandler(configurationChan chan<- types.ConfigMessage) func(response http.ResponseWriter, request *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		configuration, err := provider.cloneWebConfiguration()
+		if err != nil {
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusInternalServerError)
+			return
+		}
+		backendID := chi.URLParam(request, "backend")
+		serverID := chi.URLParam(request, "server")
+		backend, ok := configuration.Backends[backendID]
+		if !ok {
+			http.NotFound(response, request)
+			return
+		}
+		if _, ok := backend.Servers[serverID]; !ok {
+			http.NotFound(response, request)
+			return
+		}
+		delete(backend.Servers, serverID)
+		provider.applyValidatedConfiguration(response, request, configurationChan, configuration)
+	}
+}
+
+// makeDeleteFrontendHandler removes a single frontend from the "web"
+// provider configuration.
+func (provider *WebProvider) makeDeleteFrontendHandler(configurationChan chan<- types.ConfigMessage) func(response http.ResponseWriter, request *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		configuration, err := provider.cloneWebConfiguration()
+		if err != nil {
+			http.Error(response, fmt.Sprintf("%+v", err), http.StatusInternalServerError)
+			return
+		}
+		frontendID := chi.URLParam(request, "frontend")
+		if _, ok := configuration.Frontends[frontendID]; !ok {
+			http.NotFound(response, request)
+			return
+		}
+		delete(configuration.Frontends, frontendID)
+		provider.applyValidatedConfiguration(response, request, configurationChan, configuration)
+	}
+}
+
+// getWebConfiguration returns the current "web" provider configuration,
+// or an empty one if none has been set yet.
+func (provider *WebProvider) getWebConfiguration() *types.Configuration {
+	currentConfigurations := provider.server.currentConfigurations.Get().(configs)
+	if configuration, ok := currentConfigurations["web"]; ok {
+		return configuration
+	}
+	return &types.Configuration{
+		Backends:  make(map[string]*types.Backend),
+		Frontends: make(map[string]*types.Frontend),
+	}
+}
+
+// cloneWebConfiguration returns a deep copy of the current "web"
+// provider configuration. getWebConfiguration returns the live object
+// stored in currentConfigurations, which is read concurrently by every
+// GET handler and polled by the broadcaster; callers that mutate the
+// configuration (the DELETE handlers) must clone it first so they never
+// touch that shared map, and two concurrent deletes can't race on it.
+func (provider *WebProvider) cloneWebConfiguration() (*types.Configuration, error) {
+	return deepCopyConfiguration(provider.getWebConfiguration())
+}
+
+// deepCopyConfiguration round-trips configuration through JSON to
+// produce an independent copy sharing no maps or pointers with it.
+func deepCopyConfiguration(configuration *types.Configuration) (*types.Configuration, error) {
+	body, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+	clone := new(types.Configuration)
+	if err := json.Unmarshal(body, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// applyValidatedConfiguration rejects the change with a 403 when the
+// REST API is read-only, enforces the If-Match precondition against
+// the configuration currently live on the "web" provider, validates
+// configuration against the embedded JSON Schema, rejects it with a 422
+// and a structured error list on failure, and otherwise forwards it on
+// configurationChan before writing back the new representation along
+// with its ETag.
+//
+// The PATCH handler and the three DELETE handlers funnel through here,
+// so read-only mode and If-Match are honored uniformly across them. The
+// PUT handler in web.go does not go through here and does not check
+// If-Match.
+func (provider *WebProvider) applyValidatedConfiguration(response http.ResponseWriter, request *http.Request, configurationChan chan<- types.ConfigMessage, configuration *types.Configuration) {
+	if provider.ReadOnly {
+		response.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(response, "REST API is in read-only mode")
+		return
+	}
+
+	if !ifMatchSatisfied(request, provider.getWebConfiguration()) {
+		response.WriteHeader(http.StatusPreconditionFailed)
+		fmt.Fprintf(response, "If-Match does not match the current representation")
+		return
+	}
+
+	if errs := validateConfiguration(configuration); len(errs) > 0 {
+		provider.metrics.ConfigReloadsErrorsCounter()
+		response.Header().Set("Content-Type", "application/json; charset=utf-8")
+		response.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(response).Encode(validationErrors{Errors: errs})
+		return
+	}
+
+	provider.metrics.ConfigReloadsCounter()
+	configurationChan <- types.ConfigMessage{ProviderName: "web", Configuration: configuration}
+	provider.broadcaster.nudge()
+
+	etag, err := computeETag(configuration)
+	if err == nil {
+		response.Header().Set("ETag", etag)
+	}
+	templatesRenderer.JSON(response, http.StatusOK, configuration)
+}
+
+// validateConfiguration checks configuration against the hand-maintained
+// JSON Schema in autogen.ConfigurationSchema, returning a human-readable
+// error per schema violation. The schema is not derived from
+// types.Configuration's struct tags.
+func validateConfiguration(configuration *types.Configuration) []string {
+	body, err := json.Marshal(configuration)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewBytesLoader(autogen.ConfigurationSchema),
+		gojsonschema.NewBytesLoader(body),
+	)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	errs := make([]string, 0, len(result.Errors()))
+	for _, re := range result.Errors() {
+		errs = append(errs, re.String())
+	}
+	return errs
+}
+
+// ifMatchSatisfied reports whether request's If-Match header (if any)
+// matches current's ETag. A request without an If-Match header always
+// satisfies the precondition, matching the conditional-request
+// semantics of RFC 7232.
+func ifMatchSatisfied(request *http.Request, current *types.Configuration) bool {
+	ifMatch := request.Header.Get("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+	etag, err := computeETag(current)
+	if err != nil {
+		return false
+	}
+	return ifMatch == etag
+}
+
+// computeETag returns a stable, quoted hash of configuration suitable
+// for use as an HTTP ETag / If-Match value.
+func computeETag(configuration *types.Configuration) (string, error) {
+	body, err := json.Marshal(configuration)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
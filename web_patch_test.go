@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestDeepCopyConfigurationIsIndependentOfTheOriginal(t *testing.T) {
+	original := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+		Frontends: map[string]*types.Frontend{},
+	}
+
+	clone, err := deepCopyConfiguration(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	delete(clone.Backends, "backend1")
+
+	if _, ok := original.Backends["backend1"]; !ok {
+		t.Fatal("mutating the clone must not affect the original configuration")
+	}
+}
+
+func TestValidateConfigurationAcceptsAValidConfiguration(t *testing.T) {
+	configuration := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+		Frontends: map[string]*types.Frontend{
+			"frontend1": {Backend: "backend1"},
+		},
+	}
+
+	if errs := validateConfiguration(configuration); len(errs) != 0 {
+		t.Fatalf("expected a valid configuration to pass validation, got errors: %v", errs)
+	}
+}
+
+func TestValidateConfigurationRejectsAServerWithoutAURL(t *testing.T) {
+	configuration := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {},
+			}},
+		},
+	}
+
+	if errs := validateConfiguration(configuration); len(errs) == 0 {
+		t.Fatal("expected a server without a URL to fail schema validation")
+	}
+}
+
+func TestValidateConfigurationRejectsAFrontendWithoutABackend(t *testing.T) {
+	configuration := &types.Configuration{
+		Frontends: map[string]*types.Frontend{
+			"frontend1": {},
+		},
+	}
+
+	if errs := validateConfiguration(configuration); len(errs) == 0 {
+		t.Fatal("expected a frontend without a backend reference to fail schema validation")
+	}
+}
+
+func TestIfMatchSatisfiedWithoutAHeaderAlwaysPasses(t *testing.T) {
+	current := &types.Configuration{Backends: map[string]*types.Backend{}}
+	request := httptest.NewRequest(http.MethodDelete, "/api/providers/web/backends/backend1", nil)
+
+	if !ifMatchSatisfied(request, current) {
+		t.Fatal("expected a request without an If-Match header to satisfy the precondition")
+	}
+}
+
+func TestIfMatchSatisfiedRejectsAStaleETag(t *testing.T) {
+	current := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+	}
+	request := httptest.NewRequest(http.MethodDelete, "/api/providers/web/backends/backend1", nil)
+	request.Header.Set("If-Match", `"stale"`)
+
+	if ifMatchSatisfied(request, current) {
+		t.Fatal("expected a stale If-Match to fail the precondition")
+	}
+}
+
+func TestIfMatchSatisfiedAcceptsTheCurrentETag(t *testing.T) {
+	current := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+	}
+	etag, err := computeETag(current)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	request := httptest.NewRequest(http.MethodDelete, "/api/providers/web/backends/backend1", nil)
+	request.Header.Set("If-Match", etag)
+
+	if !ifMatchSatisfied(request, current) {
+		t.Fatal("expected the current ETag to satisfy the precondition")
+	}
+}
+
+func TestComputeETagIsStableAndContentAddressed(t *testing.T) {
+	configuration := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"backend1": {Servers: map[string]*types.Server{
+				"server1": {URL: "http://10.0.0.1:80"},
+			}},
+		},
+	}
+
+	first, err := computeETag(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	second, err := computeETag(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if first != second {
+		t.Errorf("expected computeETag to be stable for the same content, got %q and %q", first, second)
+	}
+
+	configuration.Backends["backend1"].Servers["server1"].URL = "http://10.0.0.2:80"
+	changed, err := computeETag(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if changed == first {
+		t.Error("expected computeETag to change when the configuration's content changes")
+	}
+}